@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+const (
+	// maxAttachmentSize is the hard cap on any attachment we'll read into
+	// memory, whether it ends up inlined or uploaded via the Files API.
+	maxAttachmentSize = 50 * 1024 * 1024 // 50MB
+
+	// maxInlineAttachmentSize is the largest attachment we'll inline
+	// directly into a request as a genai.Blob. Anything bigger is uploaded
+	// through the Files API instead and referenced by genai.FileData.
+	maxInlineAttachmentSize = 15 * 1024 * 1024 // 15MB
+)
+
+// attachmentQueue accumulates the parts attached via /image, /file, and
+// /mime slash-commands in the REPL until the next text prompt is sent.
+type attachmentQueue struct {
+	parts        []genai.Part
+	mimeOverride string
+}
+
+// handleSlashCommand processes a REPL line starting with "/". It reports
+// whether the line was a recognized attachment command, in which case it
+// should not be sent to the model as a text prompt.
+func (q *attachmentQueue) handleSlashCommand(ctx context.Context, client *genai.Client, line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "/mime":
+		if len(fields) != 2 {
+			fmt.Println("Usage: /mime <type>  (applies to the next /image or /file)")
+			return true
+		}
+		q.mimeOverride = fields[1]
+		fmt.Printf("MIME type override set to %q for the next attachment.\n", q.mimeOverride)
+		return true
+
+	case "/image", "/file":
+		if len(fields) != 2 {
+			fmt.Printf("Usage: %s <path-or-url>\n", fields[0])
+			return true
+		}
+		mimeOverride := q.mimeOverride
+		q.mimeOverride = ""
+
+		part, err := loadAttachment(ctx, client, fields[1], mimeOverride)
+		if err != nil {
+			fmt.Printf("Failed to attach %s: %v\n", fields[1], err)
+			return true
+		}
+		q.parts = append(q.parts, part)
+		fmt.Printf("Attached %s. It will be sent with your next message.\n", fields[1])
+		return true
+
+	default:
+		return false
+	}
+}
+
+// drain returns the queued attachment parts and empties the queue.
+func (q *attachmentQueue) drain() []genai.Part {
+	parts := q.parts
+	q.parts = nil
+	return parts
+}
+
+// loadAttachment reads src (a local path or an http(s) URL), determines its
+// MIME type, and returns a genai.Part for it. Attachments at or under
+// maxInlineAttachmentSize are inlined as a genai.Blob; larger ones are
+// uploaded through the Files API and referenced by genai.FileData so the
+// request body stays small. client is nil when the active provider isn't
+// Gemini, in which case large attachments can't be uploaded.
+func loadAttachment(ctx context.Context, client *genai.Client, src string, mimeOverride string) (genai.Part, error) {
+	data, sniffedMime, err := readAttachmentBytes(src)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := mimeOverride
+	if mimeType == "" {
+		mimeType = sniffedMime
+	}
+
+	if len(data) <= maxInlineAttachmentSize {
+		return genai.Blob{MIMEType: mimeType, Data: data}, nil
+	}
+
+	if client == nil {
+		return nil, fmt.Errorf("%s is larger than %d bytes; uploading large attachments requires the Gemini provider", src, maxInlineAttachmentSize)
+	}
+
+	f, err := client.UploadFile(ctx, "", bytes.NewReader(data), &genai.UploadFileOptions{MIMEType: mimeType})
+	if err != nil {
+		return nil, fmt.Errorf("uploading via Files API: %w", err)
+	}
+	return genai.FileData{MIMEType: f.MIMEType, URI: f.URI}, nil
+}
+
+// readAttachmentBytes fetches src, which may be a local file path or an
+// http(s) URL, enforcing maxAttachmentSize and sniffing a MIME type when one
+// can't be derived from the source itself.
+func readAttachmentBytes(src string) (data []byte, mimeType string, err error) {
+	if u, parseErr := url.Parse(src); parseErr == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		resp, err := http.Get(src)
+		if err != nil {
+			return nil, "", fmt.Errorf("fetching %s: %w", src, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("fetching %s: unexpected status %s", src, resp.Status)
+		}
+		data, err = io.ReadAll(io.LimitReader(resp.Body, maxAttachmentSize+1))
+		if err != nil {
+			return nil, "", fmt.Errorf("reading %s: %w", src, err)
+		}
+		mimeType = resp.Header.Get("Content-Type")
+	} else {
+		f, openErr := os.Open(src)
+		if openErr != nil {
+			return nil, "", fmt.Errorf("reading %s: %w", src, openErr)
+		}
+		defer f.Close()
+		data, err = io.ReadAll(io.LimitReader(f, maxAttachmentSize+1))
+		if err != nil {
+			return nil, "", fmt.Errorf("reading %s: %w", src, err)
+		}
+		mimeType = mime.TypeByExtension(filepath.Ext(src))
+	}
+
+	if len(data) > maxAttachmentSize {
+		return nil, "", fmt.Errorf("%s is larger than the %d byte attachment limit", src, maxAttachmentSize)
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	return data, mimeType, nil
+}
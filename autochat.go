@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"github.com/Tztedtrzh/PogBot/chatprovider"
+)
+
+// autochatConfig holds the --autochat flags.
+type autochatConfig struct {
+	ConcurrentPlayers int
+	NumChats          int
+	StopPhrase        string
+	PromptsFile       string
+}
+
+// runAutochat drives ConcurrentPlayers goroutines, each opening its own chat
+// session seeded with systemPrompt and working through the scripted prompts
+// loaded from cfg.PromptsFile. A session ends early if a reply contains
+// cfg.StopPhrase; otherwise it ends once the script is exhausted, which
+// doubles as the per-session message cap. Either way, the goroutine starts a
+// fresh session and keeps going until NumChats sessions have been started in
+// total. Metrics are printed once every goroutine has returned.
+func runAutochat(ctx context.Context, provider chatprovider.Provider, geminiModel *genai.GenerativeModel, systemPrompt string, cfg autochatConfig) error {
+	prompts, err := loadPrompts(cfg.PromptsFile)
+	if err != nil {
+		return fmt.Errorf("loading prompts file: %w", err)
+	}
+	if len(prompts) == 0 {
+		return fmt.Errorf("prompts file %q has no prompts", cfg.PromptsFile)
+	}
+
+	m := newMetrics()
+	var started int64
+
+	var wg sync.WaitGroup
+	for p := 0; p < cfg.ConcurrentPlayers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if atomic.AddInt64(&started, 1) > int64(cfg.NumChats) {
+					return
+				}
+				runOneChat(ctx, provider, geminiModel, systemPrompt, prompts, cfg.StopPhrase, m)
+			}
+		}()
+	}
+	wg.Wait()
+
+	m.Report(os.Stdout)
+	return nil
+}
+
+// runOneChat plays one scripted conversation through a fresh chat session.
+func runOneChat(ctx context.Context, provider chatprovider.Provider, geminiModel *genai.GenerativeModel, systemPrompt string, prompts []string, stopPhrase string, m *metrics) {
+	session := provider.StartChat(systemPrompt)
+
+	for _, prompt := range prompts {
+		start := time.Now()
+		resp, err := session.Send(ctx, genai.Text(prompt))
+		latency := time.Since(start)
+
+		var promptTokens, replyTokens int32
+		if geminiModel != nil {
+			if tok, tErr := geminiModel.CountTokens(ctx, genai.Text(prompt)); tErr == nil {
+				promptTokens = tok.TotalTokens
+			}
+			if err == nil {
+				if tok, tErr := geminiModel.CountTokens(ctx, genai.Text(responseText(resp))); tErr == nil {
+					replyTokens = tok.TotalTokens
+				}
+			}
+		}
+
+		m.record(latency, promptTokens, replyTokens, err)
+		if err != nil {
+			return
+		}
+
+		if stopPhrase != "" && strings.Contains(responseText(resp), stopPhrase) {
+			return
+		}
+	}
+}
+
+// responseText concatenates the text parts of a single (non-streamed)
+// response.
+func responseText(resp *genai.GenerateContentResponse) string {
+	var text strings.Builder
+	for _, cand := range resp.Candidates {
+		if cand.Content == nil {
+			continue
+		}
+		for _, part := range cand.Content.Parts {
+			if txt, ok := part.(genai.Text); ok {
+				text.WriteString(string(txt))
+			}
+		}
+	}
+	return text.String()
+}
+
+// loadPrompts reads one scripted prompt per non-blank line from path.
+func loadPrompts(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var prompts []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		prompts = append(prompts, line)
+	}
+	return prompts, scanner.Err()
+}
+
+// metrics collects per-request latency, token counts, and error rate across
+// every autochat goroutine.
+type metrics struct {
+	mu        sync.Mutex
+	start     time.Time
+	latencies []time.Duration
+	requests  int64
+	errors    int64
+	promptTok int64
+	replyTok  int64
+}
+
+func newMetrics() *metrics {
+	return &metrics{start: time.Now()}
+}
+
+func (m *metrics) record(latency time.Duration, promptTokens, replyTokens int32, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests++
+	if err != nil {
+		m.errors++
+		return
+	}
+	m.latencies = append(m.latencies, latency)
+	m.promptTok += int64(promptTokens)
+	m.replyTok += int64(replyTokens)
+}
+
+// Report prints a summary of the run: throughput, latency percentiles,
+// total tokens seen, and the error rate.
+func (m *metrics) Report(w *os.File) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elapsed := time.Since(m.start)
+	sorted := append([]time.Duration(nil), m.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var errRate float64
+	if m.requests > 0 {
+		errRate = float64(m.errors) / float64(m.requests) * 100
+	}
+
+	fmt.Fprintln(w, "--- autochat results ---")
+	fmt.Fprintf(w, "requests:      %d (%d errors, %.1f%%)\n", m.requests, m.errors, errRate)
+	fmt.Fprintf(w, "duration:      %s\n", elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "requests/sec:  %.2f\n", float64(m.requests)/elapsed.Seconds())
+	fmt.Fprintf(w, "latency p50:   %s\n", percentile(sorted, 0.50).Round(time.Millisecond))
+	fmt.Fprintf(w, "latency p95:   %s\n", percentile(sorted, 0.95).Round(time.Millisecond))
+	fmt.Fprintf(w, "prompt tokens: %d\n", m.promptTok)
+	fmt.Fprintf(w, "reply tokens:  %d\n", m.replyTok)
+}
+
+// percentile returns the p-th percentile (0 < p <= 1) of a sorted slice of
+// durations, or 0 if it's empty.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	ms := func(vals ...int) []time.Duration {
+		out := make([]time.Duration, len(vals))
+		for i, v := range vals {
+			out[i] = time.Duration(v) * time.Millisecond
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"empty", nil, 0.50, 0},
+		{"single element p50", ms(100), 0.50, 100 * time.Millisecond},
+		{"single element p95", ms(100), 0.95, 100 * time.Millisecond},
+		{"p50 picks middle-ish index", ms(10, 20, 30, 40, 50), 0.50, 30 * time.Millisecond},
+		{"p95 rounds up toward the tail", ms(10, 20, 30, 40, 50), 0.95, 50 * time.Millisecond},
+		{"p100 clamps to the last element", ms(10, 20, 30), 1.0, 30 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := percentile(tt.sorted, tt.p)
+			if got != tt.want {
+				t.Errorf("percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+			}
+		})
+	}
+}
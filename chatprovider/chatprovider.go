@@ -0,0 +1,44 @@
+// Package chatprovider defines a small backend-agnostic interface over chat
+// models so PogBot can talk to Gemini, OpenAI-compatible servers (Ollama,
+// LM Studio, OpenAI itself), or future backends through the same REPL code.
+//
+// Conversation state is expressed using genai's types (genai.Content,
+// genai.Part) regardless of which backend is actually in use. Gemini is the
+// richer of the two APIs PogBot speaks, so its types serve as the lingua
+// franca; other providers translate to and from them at their boundary.
+package chatprovider
+
+import (
+	"context"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// Provider creates chat sessions against a specific backend.
+type Provider interface {
+	// Name identifies the provider for display and the /switch command.
+	Name() string
+	// StartChat begins a new session, optionally seeded with a system
+	// instruction (PogBot's "personality" prompt).
+	StartChat(system string) Session
+}
+
+// Session is a single ongoing conversation with a provider's model.
+type Session interface {
+	// Send sends parts and returns the complete response.
+	Send(ctx context.Context, parts ...genai.Part) (*genai.GenerateContentResponse, error)
+	// SendStream sends parts and streams the response back chunk by chunk.
+	SendStream(ctx context.Context, parts ...genai.Part) StreamIterator
+	// History returns the turns exchanged so far.
+	History() []*genai.Content
+	// SetHistory replaces the session's history, e.g. after /load, /switch,
+	// or token-budget trimming.
+	SetHistory(history []*genai.Content)
+}
+
+// StreamIterator yields streamed response chunks, one per call to Next.
+// It matches the shape of *genai.GenerateContentResponseIterator, which
+// satisfies it directly.
+type StreamIterator interface {
+	Next() (*genai.GenerateContentResponse, error)
+}
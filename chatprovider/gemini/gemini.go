@@ -0,0 +1,139 @@
+// Package gemini adapts the github.com/google/generative-ai-go/genai client
+// to the chatprovider interfaces.
+package gemini
+
+import (
+	"context"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/Tztedtrzh/PogBot/chatprovider"
+)
+
+// Provider is a chatprovider.Provider backed by the Gemini API.
+type Provider struct {
+	client *genai.Client
+	model  *genai.GenerativeModel
+}
+
+// New creates a Gemini-backed provider for the given model name.
+func New(ctx context.Context, apiKey, modelName string) (*Provider, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{client: client, model: client.GenerativeModel(modelName)}, nil
+}
+
+// Client exposes the underlying genai.Client for callers that need
+// Gemini-specific functionality, such as the Files API.
+func (p *Provider) Client() *genai.Client { return p.client }
+
+// Model exposes the underlying genai.GenerativeModel for callers that need
+// Gemini-specific functionality, such as CountTokens.
+func (p *Provider) Model() *genai.GenerativeModel { return p.model }
+
+// GenerationConfig holds the subset of genai's generation parameters PogBot
+// exposes through config.yaml and the /set REPL command.
+type GenerationConfig struct {
+	Temperature     *float32
+	TopP            *float32
+	TopK            *int32
+	MaxOutputTokens *int32
+	StopSequences   []string
+}
+
+// ApplyGenerationConfig copies any non-nil/non-empty fields of cfg onto the
+// model. It can be called again at any time (e.g. from /set) to retune a
+// running session: every session takes a fresh snapshot of the model on
+// each turn (see newChatSession), so the change applies starting with the
+// very next message, no restart required.
+func (p *Provider) ApplyGenerationConfig(cfg GenerationConfig) {
+	if cfg.Temperature != nil {
+		p.model.Temperature = cfg.Temperature
+	}
+	if cfg.TopP != nil {
+		p.model.TopP = cfg.TopP
+	}
+	if cfg.TopK != nil {
+		p.model.TopK = cfg.TopK
+	}
+	if cfg.MaxOutputTokens != nil {
+		p.model.MaxOutputTokens = cfg.MaxOutputTokens
+	}
+	if len(cfg.StopSequences) > 0 {
+		p.model.StopSequences = cfg.StopSequences
+	}
+}
+
+// Close releases the underlying client's resources.
+func (p *Provider) Close() error { return p.client.Close() }
+
+func (p *Provider) Name() string { return "gemini" }
+
+// StartChat begins a new session seeded with systemPrompt. The session
+// doesn't capture the model's generation config, safety settings, or system
+// instruction at this point; newChatSession takes a fresh snapshot for
+// every turn instead, so retuning via /set or /safety reaches an
+// already-running session, and concurrent sessions (e.g. --autochat's
+// goroutines) never share a mutable model.
+func (p *Provider) StartChat(system string) chatprovider.Session {
+	return &session{provider: p, system: system}
+}
+
+// newChatSession snapshots p.model's current configuration into a private
+// copy, applies system to it, and starts a genai.ChatSession against that
+// copy seeded with history. It's called fresh for every Send/SendStream, so
+// the snapshot always reflects the latest /set or /safety change.
+func (p *Provider) newChatSession(system string, history []*genai.Content) *genai.ChatSession {
+	model := *p.model
+	if system != "" {
+		model.SystemInstruction = &genai.Content{Parts: []genai.Part{genai.Text(system)}}
+	}
+	cs := model.StartChat()
+	cs.History = history
+	return cs
+}
+
+type session struct {
+	provider *Provider
+	system   string
+	history  []*genai.Content
+}
+
+func (s *session) Send(ctx context.Context, parts ...genai.Part) (*genai.GenerateContentResponse, error) {
+	cs := s.provider.newChatSession(s.system, s.history)
+	resp, err := cs.SendMessage(ctx, parts...)
+	s.history = cs.History
+	return resp, err
+}
+
+func (s *session) SendStream(ctx context.Context, parts ...genai.Part) chatprovider.StreamIterator {
+	cs := s.provider.newChatSession(s.system, s.history)
+	it := cs.SendMessageStream(ctx, parts...)
+	s.history = cs.History
+	return &streamIterator{it: it, cs: cs, session: s}
+}
+
+func (s *session) History() []*genai.Content { return s.history }
+
+func (s *session) SetHistory(history []*genai.Content) { s.history = history }
+
+// streamIterator adapts a genai.GenerateContentResponseIterator built
+// against a per-turn model snapshot (see newChatSession) so the session's
+// own history picks up the completed reply once streaming finishes.
+type streamIterator struct {
+	it      *genai.GenerateContentResponseIterator
+	cs      *genai.ChatSession
+	session *session
+}
+
+func (it *streamIterator) Next() (*genai.GenerateContentResponse, error) {
+	resp, err := it.it.Next()
+	if err == iterator.Done {
+		it.session.history = it.cs.History
+	}
+	return resp, err
+}
@@ -0,0 +1,74 @@
+package gemini
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// harmCategories accepts both the full HARM_CATEGORY_* name and a short
+// alias so config.yaml and the /safety command can stay terse.
+var harmCategories = map[string]genai.HarmCategory{
+	"harassment":                      genai.HarmCategoryHarassment,
+	"harm_category_harassment":        genai.HarmCategoryHarassment,
+	"hate_speech":                     genai.HarmCategoryHateSpeech,
+	"hate":                            genai.HarmCategoryHateSpeech,
+	"harm_category_hate_speech":       genai.HarmCategoryHateSpeech,
+	"sexually_explicit":               genai.HarmCategorySexuallyExplicit,
+	"sexual":                          genai.HarmCategorySexuallyExplicit,
+	"harm_category_sexually_explicit": genai.HarmCategorySexuallyExplicit,
+	"dangerous_content":               genai.HarmCategoryDangerousContent,
+	"dangerous":                       genai.HarmCategoryDangerousContent,
+	"harm_category_dangerous_content": genai.HarmCategoryDangerousContent,
+}
+
+var harmThresholds = map[string]genai.HarmBlockThreshold{
+	"block_none":             genai.HarmBlockNone,
+	"none":                   genai.HarmBlockNone,
+	"block_only_high":        genai.HarmBlockOnlyHigh,
+	"only_high":              genai.HarmBlockOnlyHigh,
+	"block_medium_and_above": genai.HarmBlockMediumAndAbove,
+	"medium_and_above":       genai.HarmBlockMediumAndAbove,
+	"block_low_and_above":    genai.HarmBlockLowAndAbove,
+	"low_and_above":          genai.HarmBlockLowAndAbove,
+}
+
+// ParseHarmCategory resolves a category name from config.yaml or the
+// /safety command to a genai.HarmCategory.
+func ParseHarmCategory(name string) (genai.HarmCategory, error) {
+	category, ok := harmCategories[normalize(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown harm category %q", name)
+	}
+	return category, nil
+}
+
+// ParseHarmBlockThreshold resolves a threshold name from config.yaml or the
+// /safety command to a genai.HarmBlockThreshold.
+func ParseHarmBlockThreshold(name string) (genai.HarmBlockThreshold, error) {
+	threshold, ok := harmThresholds[normalize(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown harm block threshold %q", name)
+	}
+	return threshold, nil
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// ApplySafetySetting upserts the block threshold for category, taking
+// effect on the next turn.
+func (p *Provider) ApplySafetySetting(category genai.HarmCategory, threshold genai.HarmBlockThreshold) {
+	for _, setting := range p.model.SafetySettings {
+		if setting.Category == category {
+			setting.Threshold = threshold
+			return
+		}
+	}
+	p.model.SafetySettings = append(p.model.SafetySettings, &genai.SafetySetting{
+		Category:  category,
+		Threshold: threshold,
+	})
+}
@@ -0,0 +1,74 @@
+package gemini
+
+import (
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+func TestParseHarmCategory(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    genai.HarmCategory
+		wantErr bool
+	}{
+		{"short alias", "harassment", genai.HarmCategoryHarassment, false},
+		{"full name", "HARM_CATEGORY_HARASSMENT", genai.HarmCategoryHarassment, false},
+		{"alias with surrounding whitespace", "  hate  ", genai.HarmCategoryHateSpeech, false},
+		{"mixed case", "Dangerous_Content", genai.HarmCategoryDangerousContent, false},
+		{"unknown category", "bogus", 0, true},
+		{"empty string", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHarmCategory(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseHarmCategory(%q) = %v, nil; want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHarmCategory(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHarmCategory(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHarmBlockThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    genai.HarmBlockThreshold
+		wantErr bool
+	}{
+		{"short alias", "none", genai.HarmBlockNone, false},
+		{"full name", "BLOCK_ONLY_HIGH", genai.HarmBlockOnlyHigh, false},
+		{"alias with surrounding whitespace", "  medium_and_above  ", genai.HarmBlockMediumAndAbove, false},
+		{"unknown threshold", "bogus", 0, true},
+		{"empty string", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHarmBlockThreshold(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseHarmBlockThreshold(%q) = %v, nil; want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHarmBlockThreshold(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHarmBlockThreshold(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Package openai adapts any OpenAI-compatible chat-completions endpoint
+// (OpenAI itself, or local servers such as Ollama and LM Studio) to the
+// chatprovider interfaces.
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/api/iterator"
+
+	"github.com/Tztedtrzh/PogBot/chatprovider"
+)
+
+// GenerationParams holds the subset of OpenAI's generation parameters
+// PogBot exposes through config.yaml and the /set REPL command. A nil
+// field means "use the server's default".
+type GenerationParams struct {
+	Temperature *float32
+	TopP        *float32
+	MaxTokens   *int
+}
+
+// apply copies any non-nil fields onto req.
+func (params GenerationParams) apply(req *openai.ChatCompletionRequest) {
+	if params.Temperature != nil {
+		req.Temperature = *params.Temperature
+	}
+	if params.TopP != nil {
+		req.TopP = *params.TopP
+	}
+	if params.MaxTokens != nil {
+		req.MaxTokens = *params.MaxTokens
+	}
+}
+
+// Provider is a chatprovider.Provider backed by an OpenAI-compatible
+// chat-completions endpoint.
+type Provider struct {
+	client *openai.Client
+	model  string
+	params GenerationParams
+}
+
+// New creates an OpenAI-compatible provider. baseURL may be empty to use
+// OpenAI's own API, or point at a local server's endpoint (e.g.
+// "http://localhost:11434/v1" for Ollama).
+func New(apiKey, modelName, baseURL string) *Provider {
+	cfg := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	return &Provider{client: openai.NewClientWithConfig(cfg), model: modelName}
+}
+
+func (p *Provider) Name() string { return "openai" }
+
+// SetGenerationParams updates the parameters applied to every subsequent
+// request on any session started from p, letting /set retune a running
+// session the same way it does for Gemini. Sessions read p.params fresh on
+// every request rather than capturing it at StartChat time, so the change
+// applies starting with the very next message.
+func (p *Provider) SetGenerationParams(params GenerationParams) { p.params = params }
+
+func (p *Provider) StartChat(system string) chatprovider.Session {
+	s := &session{provider: p}
+	if system != "" {
+		s.history = append(s.history, &genai.Content{Role: "system", Parts: []genai.Part{genai.Text(system)}})
+	}
+	return s
+}
+
+type session struct {
+	provider *Provider
+	history  []*genai.Content
+}
+
+func (s *session) Send(ctx context.Context, parts ...genai.Part) (*genai.GenerateContentResponse, error) {
+	messages, err := s.appendTurn(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model:    s.provider.model,
+		Messages: messages,
+	}
+	s.provider.params.apply(&req)
+
+	resp, err := s.provider.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("openai: no choices returned")
+	}
+	text := resp.Choices[0].Message.Content
+	s.recordReply(text)
+	return textResponse(text), nil
+}
+
+func (s *session) SendStream(ctx context.Context, parts ...genai.Part) chatprovider.StreamIterator {
+	messages, err := s.appendTurn(parts)
+	if err != nil {
+		return &errIterator{err: err}
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model:    s.provider.model,
+		Messages: messages,
+		Stream:   true,
+	}
+	s.provider.params.apply(&req)
+
+	stream, err := s.provider.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return &errIterator{err: err}
+	}
+	return &streamIterator{stream: stream, session: s}
+}
+
+func (s *session) History() []*genai.Content { return s.history }
+
+func (s *session) SetHistory(history []*genai.Content) { s.history = history }
+
+// appendTurn converts parts into the next user message, appends it to
+// history, and returns the full conversation as OpenAI chat messages.
+func (s *session) appendTurn(parts []genai.Part) ([]openai.ChatCompletionMessage, error) {
+	text, err := textOnly(parts)
+	if err != nil {
+		return nil, err
+	}
+	s.history = append(s.history, &genai.Content{Role: "user", Parts: []genai.Part{genai.Text(text)}})
+	return toOpenAIMessages(s.history), nil
+}
+
+func (s *session) recordReply(text string) {
+	s.history = append(s.history, &genai.Content{Role: "model", Parts: []genai.Part{genai.Text(text)}})
+}
+
+// textOnly concatenates the text parts of a turn. Unlike Gemini, OpenAI-
+// compatible chat completions don't accept inline Blob/FileData
+// attachments, so a non-text part is reported rather than silently dropped.
+func textOnly(parts []genai.Part) (string, error) {
+	var text string
+	for _, part := range parts {
+		switch p := part.(type) {
+		case genai.Text:
+			text += string(p)
+		default:
+			return "", fmt.Errorf("openai provider: attachments are not supported, got %T", p)
+		}
+	}
+	return text, nil
+}
+
+func toOpenAIMessages(history []*genai.Content) []openai.ChatCompletionMessage {
+	messages := make([]openai.ChatCompletionMessage, 0, len(history))
+	for _, c := range history {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    toOpenAIRole(c.Role),
+			Content: contentText(c),
+		})
+	}
+	return messages
+}
+
+func toOpenAIRole(role string) string {
+	switch role {
+	case "model":
+		return openai.ChatMessageRoleAssistant
+	case "system":
+		return openai.ChatMessageRoleSystem
+	default:
+		return openai.ChatMessageRoleUser
+	}
+}
+
+func contentText(c *genai.Content) string {
+	var text string
+	for _, part := range c.Parts {
+		if t, ok := part.(genai.Text); ok {
+			text += string(t)
+		}
+	}
+	return text
+}
+
+func textResponse(text string) *genai.GenerateContentResponse {
+	return &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content: &genai.Content{Role: "model", Parts: []genai.Part{genai.Text(text)}},
+		}},
+	}
+}
+
+// streamIterator adapts an OpenAI completion stream to
+// chatprovider.StreamIterator, accumulating the full reply so it can be
+// recorded into history once the stream completes.
+type streamIterator struct {
+	stream  *openai.ChatCompletionStream
+	session *session
+	reply   strings.Builder
+}
+
+func (it *streamIterator) Next() (*genai.GenerateContentResponse, error) {
+	resp, err := it.stream.Recv()
+	if errors.Is(err, io.EOF) {
+		it.stream.Close()
+		it.session.recordReply(it.reply.String())
+		return nil, iterator.Done
+	}
+	if err != nil {
+		it.stream.Close()
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return textResponse(""), nil
+	}
+	delta := resp.Choices[0].Delta.Content
+	it.reply.WriteString(delta)
+	return textResponse(delta), nil
+}
+
+// errIterator immediately returns a fixed error, used when a stream
+// couldn't even be started.
+type errIterator struct{ err error }
+
+func (it *errIterator) Next() (*genai.GenerateContentResponse, error) { return nil, it.err }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileName is the default config file loadConfig looks for in the
+// working directory.
+const configFileName = "config.yaml"
+
+// apiKeyEnvVar lets containerized deployments supply the API key without
+// writing key.txt to disk.
+const apiKeyEnvVar = "POGBOT_API_KEY"
+
+// Config holds the application's configuration: which provider/model to
+// talk to, the generation parameters and safety settings applied to it,
+// and the personality/system prompt sent at the start of every session.
+type Config struct {
+	Provider      string `yaml:"provider"`
+	GeminiModel   string `yaml:"gemini_model"`
+	OpenAIModel   string `yaml:"openai_model"`
+	OpenAIBaseURL string `yaml:"base_url"`
+
+	Temperature     *float32 `yaml:"temperature"`
+	TopP            *float32 `yaml:"top_p"`
+	TopK            *int32   `yaml:"top_k"`
+	MaxOutputTokens *int32   `yaml:"max_output_tokens"`
+	StopSequences   []string `yaml:"stop_sequences"`
+
+	// SafetySettings maps a Gemini HarmCategory name (e.g.
+	// "HARM_CATEGORY_HARASSMENT" or the shorthand "harassment") to a
+	// HarmBlockThreshold name (e.g. "BLOCK_ONLY_HIGH" or "only_high").
+	SafetySettings map[string]string `yaml:"safety_settings"`
+
+	SystemInstruction string `yaml:"system_instruction"`
+
+	// APIKey is never read from the config file; it comes from the
+	// POGBOT_API_KEY env var or, failing that, legacy key.txt.
+	APIKey string `yaml:"-"`
+}
+
+// defaultConfig returns the configuration used when no config.yaml is
+// present.
+func defaultConfig() *Config {
+	return &Config{
+		Provider:    "gemini",
+		GeminiModel: "gemini-2.0-flash",
+		OpenAIModel: "gpt-4o-mini",
+	}
+}
+
+// loadConfig reads config.yaml if present, then layers in key.txt and
+// personality.jb for anyone still relying on the old two-file setup, and
+// finally lets POGBOT_API_KEY override the API key entirely.
+func loadConfig() (*Config, error) {
+	cfg := defaultConfig()
+
+	if data, err := os.ReadFile(configFileName); err == nil {
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", configFileName, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", configFileName, err)
+	}
+
+	if cfg.SystemInstruction == "" {
+		prompt, err := readLegacyPersonality()
+		switch {
+		case err == nil:
+			cfg.SystemInstruction = prompt
+		case os.IsNotExist(err):
+			// No personality.jb either; run with no system instruction.
+		default:
+			log.Printf("Warning: could not read personality.jb: %v", err)
+		}
+	}
+
+	apiKey, err := loadAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	cfg.APIKey = apiKey
+
+	return cfg, nil
+}
+
+// loadAPIKey resolves the API key from POGBOT_API_KEY, falling back to the
+// legacy key.txt file so existing setups keep working.
+func loadAPIKey() (string, error) {
+	if key := strings.TrimSpace(os.Getenv(apiKeyEnvVar)); key != "" {
+		return key, nil
+	}
+
+	keyBytes, err := os.ReadFile("key.txt")
+	if err != nil {
+		return "", fmt.Errorf("no API key found: set %s or create key.txt: %w", apiKeyEnvVar, err)
+	}
+	apiKey := strings.TrimSpace(string(keyBytes))
+	if apiKey == "" {
+		return "", errors.New("API key file 'key.txt' is empty")
+	}
+	return apiKey, nil
+}
+
+// readLegacyPersonality reads the pre-config.yaml personality prompt file.
+// Its absence is expected once a config.yaml with system_instruction is in
+// use, so a "not found" error is reported to the caller rather than logged
+// here.
+func readLegacyPersonality() (string, error) {
+	promptBytes, err := os.ReadFile("personality.jb")
+	if err != nil {
+		return "", err
+	}
+	return string(promptBytes), nil
+}
+
+func parseFloat32(s string) (float32, error) {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q: %w", s, err)
+	}
+	return float32(v), nil
+}
+
+func parseInt32(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q: %w", s, err)
+	}
+	return int32(v), nil
+}
+
+func formatFloat32(v *float32) string {
+	if v == nil {
+		return "(default)"
+	}
+	return strconv.FormatFloat(float64(*v), 'g', -1, 32)
+}
+
+func formatInt32(v *int32) string {
+	if v == nil {
+		return "(default)"
+	}
+	return strconv.FormatInt(int64(*v), 10)
+}
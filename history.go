@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// defaultTokenSafetyMargin is the fraction of the model's input token limit
+// that history may fill; the remaining tenth is left free as headroom, so
+// trimming kicks in before a request is rejected outright for exceeding it.
+const defaultTokenSafetyMargin = 0.9
+
+// sessionStore persists chat history to JSON files under
+// ~/.pogbot/sessions so a named conversation can be resumed across runs.
+type sessionStore struct {
+	dir string
+}
+
+// newSessionStore resolves the sessions directory and ensures it exists.
+func newSessionStore() (*sessionStore, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolving home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".pogbot", "sessions")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating sessions directory: %w", err)
+	}
+	return &sessionStore{dir: dir}, nil
+}
+
+func (s *sessionStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Save writes history to the named session file, overwriting any existing
+// content.
+func (s *sessionStore) Save(name string, history []*genai.Content) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding history: %w", err)
+	}
+	if err := os.WriteFile(s.path(name), data, 0o644); err != nil {
+		return fmt.Errorf("writing session %q: %w", name, err)
+	}
+	return nil
+}
+
+// Load reads back the history previously saved under name.
+func (s *sessionStore) Load(name string) ([]*genai.Content, error) {
+	data, err := os.ReadFile(s.path(name))
+	if err != nil {
+		return nil, fmt.Errorf("reading session %q: %w", name, err)
+	}
+	var history []*genai.Content
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("decoding session %q: %w", name, err)
+	}
+	return history, nil
+}
+
+// List returns the names of all saved sessions, sorted alphabetically.
+func (s *sessionStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions directory: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// trimHistoryToFit evicts the oldest turns from *history, in place, until it
+// fits within safetyMargin of model's input token limit. The personality
+// prompt isn't part of history (it's carried as the model's
+// SystemInstruction instead), so any turn is eligible for eviction, except
+// that the most recent turn is always kept, even if it alone exceeds the
+// limit.
+func trimHistoryToFit(ctx context.Context, model *genai.GenerativeModel, history *[]*genai.Content, safetyMargin float64) error {
+	info, err := model.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching model info: %w", err)
+	}
+	limit := int32(float64(info.InputTokenLimit) * safetyMargin)
+
+	for len(*history) > 1 {
+		resp, err := model.CountTokens(ctx, flattenHistory(*history)...)
+		if err != nil {
+			return fmt.Errorf("counting tokens: %w", err)
+		}
+		if resp.TotalTokens <= limit {
+			return nil
+		}
+		// Evict the oldest turn.
+		*history = (*history)[1:]
+	}
+	return nil
+}
+
+// flattenHistory turns a history slice into the Part arguments CountTokens
+// expects.
+func flattenHistory(history []*genai.Content) []genai.Part {
+	var parts []genai.Part
+	for _, c := range history {
+		if c == nil {
+			continue
+		}
+		parts = append(parts, c.Parts...)
+	}
+	return parts
+}
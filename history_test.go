@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+func TestFlattenHistory(t *testing.T) {
+	text := func(s string) genai.Part { return genai.Text(s) }
+
+	tests := []struct {
+		name    string
+		history []*genai.Content
+		want    []genai.Part
+	}{
+		{"empty history", nil, nil},
+		{
+			"single turn",
+			[]*genai.Content{
+				{Role: "user", Parts: []genai.Part{text("hi")}},
+			},
+			[]genai.Part{text("hi")},
+		},
+		{
+			"multiple turns concatenate in order",
+			[]*genai.Content{
+				{Role: "user", Parts: []genai.Part{text("hi")}},
+				{Role: "model", Parts: []genai.Part{text("hello"), text("!")}},
+			},
+			[]genai.Part{text("hi"), text("hello"), text("!")},
+		},
+		{
+			"nil entries are skipped, not dereferenced",
+			[]*genai.Content{
+				{Role: "user", Parts: []genai.Part{text("hi")}},
+				nil,
+				{Role: "model", Parts: []genai.Part{text("hello")}},
+			},
+			[]genai.Part{text("hi"), text("hello")},
+		},
+		{
+			"entry with no parts contributes nothing",
+			[]*genai.Content{
+				{Role: "user", Parts: nil},
+			},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := flattenHistory(tt.history)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flattenHistory(%+v) = %+v, want %+v", tt.history, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,139 +1,615 @@
-package main
-
-import (
-	"bufio"
-	"context"
-	"errors"
-	"fmt"
-	"log"
-	"os"
-	"strings"
-
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
-)
-
-// Config holds the application's configuration.
-type Config struct {
-	APIKey        string
-	InitialPrompt string
-}
-
-// loadConfig reads the API key and initial prompt from their respective files.
-func loadConfig() (*Config, error) {
-	// Read the API key from key.txt
-	keyBytes, err := os.ReadFile("key.txt")
-	if err != nil {
-		return nil, fmt.Errorf("failed to read API key from key.txt: %w. Please ensure the file exists", err)
-	}
-	apiKey := strings.TrimSpace(string(keyBytes))
-	if apiKey == "" {
-		return nil, errors.New("API key file 'key.txt' is empty")
-	}
-
-	// Read the initial personality prompt from personality.jb
-	// This part is optional, so we handle a "not found" error gracefully.
-	promptBytes, err := os.ReadFile("personality.jb")
-	var initialPrompt string
-	if err != nil {
-		if os.IsNotExist(err) {
-			log.Println("No 'personality.jb' file found, starting a standard chat session.")
-		} else {
-			// For any other error, we should probably know about it.
-			log.Printf("Warning: could not read personality.jb: %v", err)
-		}
-	} else {
-		initialPrompt = string(promptBytes)
-	}
-
-	return &Config{
-		APIKey:        apiKey,
-		InitialPrompt: initialPrompt,
-	}, nil
-}
-
-// runChatSession handles the main interactive loop with the user.
-func runChatSession(cs *genai.ChatSession, ctx context.Context) {
-	fmt.Println("Your conversational AI is ready. Type 'quit' to exit.")
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for {
-		fmt.Print("You: ")
-		if !scanner.Scan() {
-			if err := scanner.Err(); err != nil {
-				log.Printf("Error reading input: %v", err)
-			}
-			break
-		}
-		userInput := scanner.Text()
-
-		if strings.ToLower(userInput) == "quit" {
-			fmt.Println("Goodbye!")
-			break
-		}
-
-		if userInput == "" {
-			continue
-		}
-
-		fmt.Print("Gemini: ...") // Provide instant feedback
-		resp, err := cs.SendMessage(ctx, genai.Text(userInput))
-		if err != nil {
-			fmt.Print("\r") // Clear the "Gemini: ..." line
-			log.Printf("Error sending message: %v", err)
-			continue
-		}
-		fmt.Print("\r") // Clear the "Gemini: ..." line
-		printResponse(resp)
-	}
-}
-
-// printResponse iterates through the model's response and prints the text.
-func printResponse(resp *genai.GenerateContentResponse) {
-	fmt.Print("Gemini: ")
-	for _, cand := range resp.Candidates {
-		if cand.Content != nil {
-			for _, part := range cand.Content.Parts {
-				if txt, ok := part.(genai.Text); ok {
-					fmt.Print(txt)
-				}
-			}
-		}
-	}
-	fmt.Println() // Add a newline for better formatting
-}
-
-func main() {
-	// --- 1. Load Configuration ---
-	cfg, err := loadConfig()
-	if err != nil {
-		log.Fatalf("Initialization failed: %v", err)
-	}
-
-	// The context will manage the lifecycle of our API requests.
-	ctx := context.Background()
-
-	// --- 2. Initialize the AI Client ---
-	client, err := genai.NewClient(ctx, option.WithAPIKey(cfg.APIKey))
-	if err != nil {
-		log.Fatalf("Failed to create AI client: %v", err)
-	}
-	defer client.Close()
-
-	model := client.GenerativeModel("gemini-2.0-flash")
-
-	// --- 3. Start a Chat Session ---
-	cs := model.StartChat()
-
-	// If an initial prompt was loaded, send it to the model first to set the personality.
-	if cfg.InitialPrompt != "" {
-		log.Println("Sending initial personality prompt...")
-		_, err := cs.SendMessage(ctx, genai.Text(cfg.InitialPrompt))
-		if err != nil {
-			log.Fatalf("Failed to send initial prompt: %v", err)
-		}
-	}
-
-	// --- 4. Run the main application loop ---
-	runChatSession(cs, ctx)
-}
\ No newline at end of file
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+
+	"github.com/Tztedtrzh/PogBot/chatprovider"
+	"github.com/Tztedtrzh/PogBot/chatprovider/gemini"
+	"github.com/Tztedtrzh/PogBot/chatprovider/openai"
+	"github.com/Tztedtrzh/PogBot/tools"
+)
+
+// chatREPL bundles the state needed to run the interactive loop: the active
+// provider-agnostic session, the registered providers available to /switch
+// between, and the slash-command state (queued attachments, the active
+// saved-session name) that persists across turns.
+type chatREPL struct {
+	active         chatprovider.Session
+	activeName     string
+	activeProvider chatprovider.Provider
+	providers      map[string]chatprovider.Provider
+	ctx            context.Context
+
+	attachments  attachmentQueue
+	sessions     *sessionStore
+	sessionName  string
+	systemPrompt string
+	cfg          *Config
+	tools        *tools.Registry
+	scanner      *bufio.Scanner
+
+	// geminiModel and geminiClient back Gemini-specific functionality
+	// (Files API uploads, token-budget trimming via CountTokens) that has
+	// no equivalent in the generic chatprovider interfaces. Both are nil
+	// whenever a non-Gemini provider is active.
+	geminiModel  *genai.GenerativeModel
+	geminiClient *genai.Client
+}
+
+// run handles the main interactive loop with the user.
+//
+// Responses are streamed token-by-token so the user sees text appear as the
+// model generates it, rather than waiting for the full reply. A Ctrl+C
+// during an in-flight stream aborts just that stream, leaving the session
+// (and the REPL) alive for the next prompt. Slash-commands manage
+// attachments (/image, /file, /mime), history (/save, /load, /reset,
+// /list), and the active provider (/switch).
+func (r *chatREPL) run() {
+	fmt.Println("Your conversational AI is ready. Type 'quit' to exit.")
+	fmt.Println("Use /image, /file, /mime, /save, /load, /reset, /list, /switch, /set, /show, and /safety to manage media, history, providers, and tuning.")
+	if r.scanner == nil {
+		r.scanner = bufio.NewScanner(os.Stdin)
+	}
+
+	for {
+		fmt.Printf("You (%s): ", r.activeName)
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				log.Printf("Error reading input: %v", err)
+			}
+			break
+		}
+		userInput := r.scanner.Text()
+
+		if strings.ToLower(userInput) == "quit" {
+			fmt.Println("Goodbye!")
+			break
+		}
+
+		if userInput == "" {
+			continue
+		}
+
+		if strings.HasPrefix(userInput, "/") && r.handleCommand(userInput) {
+			continue
+		}
+
+		r.send(userInput)
+	}
+}
+
+// handleCommand processes a REPL line starting with "/". It reports
+// whether the line was a recognized command, in which case it should not
+// be sent to the model as a text prompt.
+func (r *chatREPL) handleCommand(line string) bool {
+	if r.attachments.handleSlashCommand(r.ctx, r.geminiClient, line) {
+		return true
+	}
+
+	fields := strings.Fields(line)
+
+	switch fields[0] {
+	case "/save":
+		name := r.sessionName
+		if len(fields) == 2 {
+			name = fields[1]
+		}
+		if name == "" {
+			fmt.Println("Usage: /save <name> (or start with --session <name> to set a default)")
+			return true
+		}
+		if err := r.sessions.Save(name, r.active.History()); err != nil {
+			fmt.Printf("Failed to save session %q: %v\n", name, err)
+			return true
+		}
+		r.sessionName = name
+		fmt.Printf("Saved session %q (%d turns).\n", name, len(r.active.History()))
+		return true
+
+	case "/load":
+		if len(fields) != 2 {
+			fmt.Println("Usage: /load <name>")
+			return true
+		}
+		history, err := r.sessions.Load(fields[1])
+		if err != nil {
+			fmt.Printf("Failed to load session %q: %v\n", fields[1], err)
+			return true
+		}
+		r.active.SetHistory(history)
+		r.sessionName = fields[1]
+		fmt.Printf("Loaded session %q (%d turns).\n", fields[1], len(history))
+		return true
+
+	case "/reset":
+		r.active.SetHistory(nil)
+		fmt.Println("Conversation history reset.")
+		return true
+
+	case "/list":
+		names, err := r.sessions.List()
+		if err != nil {
+			fmt.Printf("Failed to list sessions: %v\n", err)
+			return true
+		}
+		if len(names) == 0 {
+			fmt.Println("No saved sessions yet.")
+			return true
+		}
+		fmt.Println("Saved sessions:")
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+		return true
+
+	case "/switch":
+		if len(fields) != 2 {
+			fmt.Printf("Usage: /switch <provider>. Available: %s\n", strings.Join(r.providerNames(), ", "))
+			return true
+		}
+		r.switchProvider(fields[1])
+		return true
+
+	case "/set":
+		if len(fields) != 3 {
+			fmt.Println("Usage: /set <temperature|top_p|top_k|max_output_tokens> <value>")
+			return true
+		}
+		if err := r.setGenerationParam(fields[1], fields[2]); err != nil {
+			fmt.Printf("Failed to set %s: %v\n", fields[1], err)
+		}
+		return true
+
+	case "/show":
+		if len(fields) != 2 || fields[1] != "config" {
+			fmt.Println("Usage: /show config")
+			return true
+		}
+		r.showConfig()
+		return true
+
+	case "/safety":
+		if len(fields) != 3 {
+			fmt.Println("Usage: /safety <category> <threshold>")
+			return true
+		}
+		if err := r.setSafety(fields[1], fields[2]); err != nil {
+			fmt.Printf("Failed to set safety setting: %v\n", err)
+		}
+		return true
+
+	default:
+		return false
+	}
+}
+
+// switchProvider activates the named provider, carrying the current
+// conversation's history over to a fresh session on that provider.
+func (r *chatREPL) switchProvider(name string) {
+	provider, ok := r.providers[name]
+	if !ok {
+		fmt.Printf("Unknown provider %q. Available: %s\n", name, strings.Join(r.providerNames(), ", "))
+		return
+	}
+
+	history := r.active.History()
+	session := provider.StartChat(r.systemPrompt)
+	seeded := session.History()
+	merged := mergeSwitchHistory(seeded, history)
+	session.SetHistory(merged)
+
+	r.active = session
+	r.activeName = name
+	r.activeProvider = provider
+	if geminiProvider, ok := provider.(*gemini.Provider); ok {
+		r.geminiModel = geminiProvider.Model()
+		r.geminiClient = geminiProvider.Client()
+	} else {
+		r.geminiModel = nil
+		r.geminiClient = nil
+	}
+
+	fmt.Printf("Switched to %q, carrying over %d turns of history.\n", name, len(merged)-len(seeded))
+}
+
+// mergeSwitchHistory combines the history StartChat just seeded on the
+// destination provider (e.g. OpenAI's system-role entry, which Gemini
+// carries via SystemInstruction instead) with the conversation turns carried
+// over from the previous provider. Any system-role entries in carried are
+// dropped so the destination's own system prompt isn't duplicated or lost.
+func mergeSwitchHistory(seeded, carried []*genai.Content) []*genai.Content {
+	merged := append([]*genai.Content(nil), seeded...)
+	for _, c := range carried {
+		if c.Role == "system" {
+			continue
+		}
+		merged = append(merged, c)
+	}
+	return merged
+}
+
+// providerNames lists the registered provider names, for help text.
+func (r *chatREPL) providerNames() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// setGenerationParam updates a single generation parameter on the active
+// provider and records it in r.cfg so /show config reflects it.
+func (r *chatREPL) setGenerationParam(name, value string) error {
+	switch name {
+	case "temperature":
+		v, err := parseFloat32(value)
+		if err != nil {
+			return err
+		}
+		r.cfg.Temperature = &v
+	case "top_p":
+		v, err := parseFloat32(value)
+		if err != nil {
+			return err
+		}
+		r.cfg.TopP = &v
+	case "top_k":
+		v, err := parseInt32(value)
+		if err != nil {
+			return err
+		}
+		r.cfg.TopK = &v
+	case "max_output_tokens":
+		v, err := parseInt32(value)
+		if err != nil {
+			return err
+		}
+		r.cfg.MaxOutputTokens = &v
+	default:
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	r.applyGenerationParams()
+	fmt.Printf("Set %s to %s.\n", name, value)
+	return nil
+}
+
+// applyGenerationParams pushes r.cfg's generation parameters onto whichever
+// provider is active.
+func (r *chatREPL) applyGenerationParams() {
+	switch p := r.activeProvider.(type) {
+	case *gemini.Provider:
+		p.ApplyGenerationConfig(gemini.GenerationConfig{
+			Temperature:     r.cfg.Temperature,
+			TopP:            r.cfg.TopP,
+			TopK:            r.cfg.TopK,
+			MaxOutputTokens: r.cfg.MaxOutputTokens,
+			StopSequences:   r.cfg.StopSequences,
+		})
+	case *openai.Provider:
+		var maxTokens *int
+		if r.cfg.MaxOutputTokens != nil {
+			v := int(*r.cfg.MaxOutputTokens)
+			maxTokens = &v
+		}
+		p.SetGenerationParams(openai.GenerationParams{
+			Temperature: r.cfg.Temperature,
+			TopP:        r.cfg.TopP,
+			MaxTokens:   maxTokens,
+		})
+	}
+}
+
+// setSafety sets a Gemini safety threshold for category, taking effect on
+// the next turn. It's a no-op (with an explanatory error) on other
+// providers, which don't expose per-category safety controls here.
+func (r *chatREPL) setSafety(category, threshold string) error {
+	geminiProvider, ok := r.activeProvider.(*gemini.Provider)
+	if !ok {
+		return fmt.Errorf("safety settings are only configurable on the gemini provider")
+	}
+
+	cat, err := gemini.ParseHarmCategory(category)
+	if err != nil {
+		return err
+	}
+	thresh, err := gemini.ParseHarmBlockThreshold(threshold)
+	if err != nil {
+		return err
+	}
+
+	geminiProvider.ApplySafetySetting(cat, thresh)
+	if r.cfg.SafetySettings == nil {
+		r.cfg.SafetySettings = map[string]string{}
+	}
+	r.cfg.SafetySettings[category] = threshold
+	fmt.Printf("Set safety threshold for %s to %s.\n", category, threshold)
+	return nil
+}
+
+// showConfig prints the active provider/model and generation parameters.
+func (r *chatREPL) showConfig() {
+	fmt.Printf("provider: %s\n", r.activeName)
+	fmt.Printf("model: %s\n", r.currentModelName())
+	fmt.Printf("temperature: %s\n", formatFloat32(r.cfg.Temperature))
+	fmt.Printf("top_p: %s\n", formatFloat32(r.cfg.TopP))
+	fmt.Printf("top_k: %s\n", formatInt32(r.cfg.TopK))
+	fmt.Printf("max_output_tokens: %s\n", formatInt32(r.cfg.MaxOutputTokens))
+	fmt.Printf("stop_sequences: %v\n", r.cfg.StopSequences)
+	fmt.Printf("safety_settings: %v\n", r.cfg.SafetySettings)
+}
+
+// currentModelName returns the model name of the active provider.
+func (r *chatREPL) currentModelName() string {
+	switch r.activeName {
+	case "gemini":
+		return r.cfg.GeminiModel
+	case "openai":
+		return r.cfg.OpenAIModel
+	default:
+		return "unknown"
+	}
+}
+
+// send sends userInput, together with any queued attachment parts, to the
+// active provider. Before sending, it trims the history so a Gemini
+// request stays under the model's input token limit; other providers don't
+// yet expose a CountTokens equivalent.
+func (r *chatREPL) send(userInput string) {
+	if r.geminiModel != nil {
+		history := r.active.History()
+		if err := trimHistoryToFit(r.ctx, r.geminiModel, &history, defaultTokenSafetyMargin); err != nil {
+			log.Printf("Warning: could not enforce token budget: %v", err)
+		} else {
+			r.active.SetHistory(history)
+		}
+	}
+
+	parts := append([]genai.Part{genai.Text(userInput)}, r.attachments.drain()...)
+	r.converse(parts)
+}
+
+// converse drives one user turn to completion: it streams the reply,
+// printing text as it arrives, and transparently runs any tool calls the
+// model asks for, feeding their results back, until a turn produces no
+// further tool calls.
+func (r *chatREPL) converse(parts []genai.Part) {
+	for {
+		streamCtx, stop := signal.NotifyContext(r.ctx, os.Interrupt)
+
+		iter := r.active.SendStream(streamCtx, parts...)
+
+		fmt.Printf("%s: ", r.activeName)
+		var calls []genai.FunctionCall
+		printedAny := false
+		for {
+			resp, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				stop()
+				if printedAny {
+					fmt.Println()
+				}
+				switch {
+				case errors.Is(err, context.Canceled):
+					fmt.Println("[stream interrupted]")
+				default:
+					log.Printf("Error streaming response: %v", err)
+				}
+				return
+			}
+			printedAny = printResponseChunk(resp) || printedAny
+			calls = append(calls, functionCalls(resp)...)
+		}
+		stop()
+		fmt.Println()
+
+		if len(calls) == 0 {
+			return
+		}
+
+		// Start a fresh slice: the session's history may still reference the
+		// previous parts slice, so reusing its backing array would overwrite
+		// the turn just sent.
+		parts = nil
+		for _, call := range calls {
+			parts = append(parts, genai.FunctionResponse{
+				Name:     call.Name,
+				Response: r.tools.Call(r.ctx, call),
+			})
+		}
+	}
+}
+
+// functionCalls extracts any FunctionCall parts from a streamed chunk.
+func functionCalls(resp *genai.GenerateContentResponse) []genai.FunctionCall {
+	var calls []genai.FunctionCall
+	for _, cand := range resp.Candidates {
+		if cand.Content == nil {
+			continue
+		}
+		for _, part := range cand.Content.Parts {
+			if call, ok := part.(genai.FunctionCall); ok {
+				calls = append(calls, call)
+			}
+		}
+	}
+	return calls
+}
+
+// confirmTool prompts the user before running a Dangerous tool, reusing
+// the REPL's input scanner since this only ever runs synchronously between
+// reads of the next user prompt.
+func (r *chatREPL) confirmTool(name string, args map[string]any) bool {
+	fmt.Printf("The model wants to run %q with args %v. Allow? [y/N] ", name, args)
+	if !r.scanner.Scan() {
+		return false
+	}
+	answer := strings.ToLower(strings.TrimSpace(r.scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
+
+// printResponseChunk prints the text parts of a single streamed chunk and
+// reports whether anything was written.
+func printResponseChunk(resp *genai.GenerateContentResponse) bool {
+	wrote := false
+	for _, cand := range resp.Candidates {
+		if cand.Content == nil {
+			continue
+		}
+		for _, part := range cand.Content.Parts {
+			if txt, ok := part.(genai.Text); ok {
+				fmt.Print(txt)
+				wrote = true
+			}
+		}
+	}
+	return wrote
+}
+
+func main() {
+	sessionFlag := flag.String("session", "", "name of a saved session to resume (see ~/.pogbot/sessions)")
+	autochatFlag := flag.Bool("autochat", false, "run a non-interactive load test instead of the REPL")
+	concurrentPlayers := flag.Int("concurrent-players", 10, "autochat: number of chat sessions to run concurrently")
+	numChats := flag.Int("num-chats", 100, "autochat: total number of scripted chat sessions to run")
+	stopPhrase := flag.String("stop-phrase", "", "autochat: end a session early if a reply contains this phrase")
+	promptsFile := flag.String("prompts-file", "", "autochat: path to a file of newline-separated scripted prompts")
+	flag.Parse()
+
+	// --- 1. Load Configuration ---
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Initialization failed: %v", err)
+	}
+
+	// The context will manage the lifecycle of our API requests.
+	ctx := context.Background()
+
+	// --- 2. Initialize the providers ---
+	geminiProvider, err := gemini.New(ctx, cfg.APIKey, cfg.GeminiModel)
+	if err != nil {
+		log.Fatalf("Failed to create Gemini client: %v", err)
+	}
+	defer geminiProvider.Close()
+	geminiProvider.ApplyGenerationConfig(gemini.GenerationConfig{
+		Temperature:     cfg.Temperature,
+		TopP:            cfg.TopP,
+		TopK:            cfg.TopK,
+		MaxOutputTokens: cfg.MaxOutputTokens,
+		StopSequences:   cfg.StopSequences,
+	})
+	for category, threshold := range cfg.SafetySettings {
+		cat, err := gemini.ParseHarmCategory(category)
+		if err != nil {
+			log.Fatalf("Invalid safety_settings category %q: %v", category, err)
+		}
+		thresh, err := gemini.ParseHarmBlockThreshold(threshold)
+		if err != nil {
+			log.Fatalf("Invalid safety_settings threshold %q: %v", threshold, err)
+		}
+		geminiProvider.ApplySafetySetting(cat, thresh)
+	}
+
+	// --- Tools the model can call ---
+	toolRegistry := tools.NewRegistry()
+	toolRegistry.Register(tools.CurrentTime())
+	toolRegistry.Register(tools.HTTPGet())
+	readFileTool, err := tools.ReadFile("sandbox")
+	if err != nil {
+		log.Fatalf("Failed to set up read_file tool: %v", err)
+	}
+	toolRegistry.Register(readFileTool)
+	// cat and grep are deliberately excluded: they'd let the model read any
+	// file the process can see, bypassing read_file's sandbox confinement.
+	toolRegistry.Register(tools.ShellExec([]string{"ls", "pwd", "date", "echo"}))
+	geminiProvider.Model().Tools = toolRegistry.GenaiTools()
+
+	openaiProvider := openai.New(cfg.APIKey, cfg.OpenAIModel, cfg.OpenAIBaseURL)
+	var openaiMaxTokens *int
+	if cfg.MaxOutputTokens != nil {
+		v := int(*cfg.MaxOutputTokens)
+		openaiMaxTokens = &v
+	}
+	openaiProvider.SetGenerationParams(openai.GenerationParams{
+		Temperature: cfg.Temperature,
+		TopP:        cfg.TopP,
+		MaxTokens:   openaiMaxTokens,
+	})
+
+	providers := map[string]chatprovider.Provider{
+		geminiProvider.Name(): geminiProvider,
+		openaiProvider.Name(): openaiProvider,
+	}
+
+	active, ok := providers[cfg.Provider]
+	if !ok {
+		log.Fatalf("Unknown provider %q in config", cfg.Provider)
+	}
+
+	sessions, err := newSessionStore()
+	if err != nil {
+		log.Fatalf("Failed to initialize session storage: %v", err)
+	}
+
+	// --- 3. Start a Chat Session ---
+	session := active.StartChat(cfg.SystemInstruction)
+
+	if *sessionFlag != "" {
+		if history, err := sessions.Load(*sessionFlag); err != nil {
+			log.Printf("Starting new session %q: %v", *sessionFlag, err)
+		} else {
+			session.SetHistory(history)
+			log.Printf("Resumed session %q (%d turns).", *sessionFlag, len(history))
+		}
+	}
+
+	repl := &chatREPL{
+		active:         session,
+		activeName:     cfg.Provider,
+		activeProvider: active,
+		providers:      providers,
+		ctx:            ctx,
+		sessions:       sessions,
+		sessionName:    *sessionFlag,
+		systemPrompt:   cfg.SystemInstruction,
+		cfg:            cfg,
+		tools:          toolRegistry,
+	}
+	if cfg.Provider == geminiProvider.Name() {
+		repl.geminiModel = geminiProvider.Model()
+		repl.geminiClient = geminiProvider.Client()
+	}
+	toolRegistry.Confirm = repl.confirmTool
+
+	// --- 4. Run the main application loop ---
+	if *autochatFlag {
+		if *promptsFile == "" {
+			log.Fatal("--autochat requires --prompts-file")
+		}
+		acCfg := autochatConfig{
+			ConcurrentPlayers: *concurrentPlayers,
+			NumChats:          *numChats,
+			StopPhrase:        *stopPhrase,
+			PromptsFile:       *promptsFile,
+		}
+		if err := runAutochat(ctx, active, repl.geminiModel, cfg.SystemInstruction, acCfg); err != nil {
+			log.Fatalf("autochat failed: %v", err)
+		}
+		return
+	}
+
+	repl.run()
+}
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// maxToolResponseBytes caps how much output a built-in tool returns to the
+// model, so a large file or a chatty command doesn't blow the
+// conversation's token budget.
+const maxToolResponseBytes = 64 * 1024
+
+// CurrentTime returns a tool that reports the current date and time.
+func CurrentTime() Tool {
+	return Tool{
+		Declaration: &genai.FunctionDeclaration{
+			Name:        "current_time",
+			Description: "Get the current date and time.",
+		},
+		Handler: func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			return map[string]any{"time": time.Now().Format(time.RFC3339)}, nil
+		},
+	}
+}
+
+// HTTPGet returns a tool that fetches a URL and returns its body, truncated
+// to maxToolResponseBytes.
+func HTTPGet() Tool {
+	return Tool{
+		Declaration: &genai.FunctionDeclaration{
+			Name:        "http_get",
+			Description: "Fetch the contents of a URL via an HTTP GET request.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"url": {Type: genai.TypeString, Description: "The URL to fetch."},
+				},
+				Required: []string{"url"},
+			},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			target, _ := args["url"].(string)
+			if target == "" {
+				return nil, fmt.Errorf("missing required argument %q", "url")
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(io.LimitReader(resp.Body, maxToolResponseBytes))
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				"status": resp.Status,
+				"body":   string(body),
+			}, nil
+		},
+	}
+}
+
+// ReadFile returns a tool that reads a text file from within sandboxDir.
+// Paths that would escape sandboxDir via ".." are rejected; an absolute
+// path is resolved relative to sandboxDir rather than the filesystem root.
+// Symbolic links inside sandboxDir are followed.
+func ReadFile(sandboxDir string) (Tool, error) {
+	root, err := filepath.Abs(sandboxDir)
+	if err != nil {
+		return Tool{}, fmt.Errorf("resolving sandbox directory: %w", err)
+	}
+
+	return Tool{
+		Declaration: &genai.FunctionDeclaration{
+			Name:        "read_file",
+			Description: fmt.Sprintf("Read a text file from the sandbox directory (%s).", root),
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"path": {Type: genai.TypeString, Description: "Path relative to the sandbox directory."},
+				},
+				Required: []string{"path"},
+			},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			rel, _ := args["path"].(string)
+			if rel == "" {
+				return nil, fmt.Errorf("missing required argument %q", "path")
+			}
+
+			path, err := sandboxedPath(root, rel)
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if len(data) > maxToolResponseBytes {
+				data = data[:maxToolResponseBytes]
+			}
+			return map[string]any{"content": string(data)}, nil
+		},
+	}, nil
+}
+
+// sandboxedPath joins root and rel, rejecting the result if it would
+// escape root.
+func sandboxedPath(root, rel string) (string, error) {
+	path := filepath.Join(root, rel)
+	if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the sandbox directory", rel)
+	}
+	return path, nil
+}
+
+// ShellExec returns a dangerous tool that runs one of the allowlisted
+// commands with model-supplied arguments. Only commands named in allowlist
+// can run, and each is executed directly (never through a shell), so
+// arbitrary shell syntax like pipes or "; rm -rf" is never interpreted.
+func ShellExec(allowlist []string) Tool {
+	allowed := make(map[string]bool, len(allowlist))
+	for _, name := range allowlist {
+		allowed[name] = true
+	}
+
+	return Tool{
+		Dangerous: true,
+		Declaration: &genai.FunctionDeclaration{
+			Name:        "shell_exec",
+			Description: fmt.Sprintf("Run an allowlisted shell command. Allowed commands: %s.", strings.Join(allowlist, ", ")),
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"command": {Type: genai.TypeString, Description: `The command name, e.g. "ls".`},
+					"args": {
+						Type:        genai.TypeArray,
+						Items:       &genai.Schema{Type: genai.TypeString},
+						Description: "Arguments to pass to the command.",
+					},
+				},
+				Required: []string{"command"},
+			},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (map[string]any, error) {
+			command, _ := args["command"].(string)
+			if !allowed[command] {
+				return nil, fmt.Errorf("command %q is not allowlisted", command)
+			}
+
+			var cmdArgs []string
+			if raw, ok := args["args"].([]any); ok {
+				for _, a := range raw {
+					if s, ok := a.(string); ok {
+						cmdArgs = append(cmdArgs, s)
+					}
+				}
+			}
+
+			var stdout, stderr bytes.Buffer
+			cmd := exec.CommandContext(ctx, command, cmdArgs...)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			runErr := cmd.Run()
+
+			result := map[string]any{
+				"stdout": truncate(stdout.String()),
+				"stderr": truncate(stderr.String()),
+			}
+			if runErr != nil {
+				result["error"] = runErr.Error()
+			}
+			return result, nil
+		},
+	}
+}
+
+func truncate(s string) string {
+	if len(s) > maxToolResponseBytes {
+		return s[:maxToolResponseBytes]
+	}
+	return s
+}
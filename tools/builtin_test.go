@@ -0,0 +1,39 @@
+package tools
+
+import "testing"
+
+func TestSandboxedPath(t *testing.T) {
+	const root = "/sandbox"
+
+	tests := []struct {
+		name    string
+		rel     string
+		want    string
+		wantErr bool
+	}{
+		{"simple file", "notes.txt", "/sandbox/notes.txt", false},
+		{"nested file", "a/b/c.txt", "/sandbox/a/b/c.txt", false},
+		{"root itself", ".", "/sandbox", false},
+		{"parent traversal", "../etc/passwd", "", true},
+		{"parent traversal after descending", "a/../../etc/passwd", "", true},
+		{"sibling directory sharing a prefix", "../sandbox-evil/x", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sandboxedPath(root, tt.rel)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("sandboxedPath(%q, %q) = %q, nil; want error", root, tt.rel, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sandboxedPath(%q, %q) unexpected error: %v", root, tt.rel, err)
+			}
+			if got != tt.want {
+				t.Errorf("sandboxedPath(%q, %q) = %q, want %q", root, tt.rel, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package tools lets PogBot expose Go functions to the model as callable
+// tools. A Registry tracks the tools available to a session, exposes them
+// to Gemini as genai.Tool declarations, and runs the matching Handler
+// whenever the model emits a FunctionCall.
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// Handler executes a single tool call. args is the decoded arguments the
+// model supplied; the returned map becomes the tool's FunctionResponse
+// payload.
+type Handler func(ctx context.Context, args map[string]any) (map[string]any, error)
+
+// Tool pairs a genai function declaration with the Go handler that
+// implements it.
+type Tool struct {
+	Declaration *genai.FunctionDeclaration
+	Handler     Handler
+	// Dangerous tools are only run after Registry.Confirm approves them.
+	Dangerous bool
+}
+
+// Registry holds the set of tools available to a chat session.
+type Registry struct {
+	tools map[string]Tool
+
+	// Confirm is consulted before running a Dangerous tool; it should
+	// return whether the caller approved the call. A nil Confirm denies
+	// every dangerous tool.
+	Confirm func(name string, args map[string]any) bool
+}
+
+// NewRegistry creates an empty tool registry.
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]Tool)}
+}
+
+// Register adds a tool. Registering a tool under a name that's already
+// registered replaces it, so a plugin can override a built-in.
+func (r *Registry) Register(t Tool) {
+	r.tools[t.Declaration.Name] = t
+}
+
+// GenaiTools returns the registered tools as a genai.Tool slice, ready to
+// be attached to a GenerativeModel via model.Tools. It returns nil when no
+// tools are registered, so callers can assign it unconditionally.
+func (r *Registry) GenaiTools() []*genai.Tool {
+	if len(r.tools) == 0 {
+		return nil
+	}
+	decls := make([]*genai.FunctionDeclaration, 0, len(r.tools))
+	for _, t := range r.tools {
+		decls = append(decls, t.Declaration)
+	}
+	return []*genai.Tool{{FunctionDeclarations: decls}}
+}
+
+// Call runs the handler for call.Name and returns its result as a
+// FunctionResponse payload. Unknown tools and declined confirmations come
+// back as an {"error": ...} payload rather than failing the turn, so the
+// model can see what went wrong and react.
+func (r *Registry) Call(ctx context.Context, call genai.FunctionCall) map[string]any {
+	tool, ok := r.tools[call.Name]
+	if !ok {
+		return errorResult(fmt.Errorf("unknown tool %q", call.Name))
+	}
+
+	if tool.Dangerous && (r.Confirm == nil || !r.Confirm(call.Name, call.Args)) {
+		return errorResult(fmt.Errorf("call to %q was not confirmed", call.Name))
+	}
+
+	result, err := tool.Handler(ctx, call.Args)
+	if err != nil {
+		return errorResult(err)
+	}
+	return result
+}
+
+func errorResult(err error) map[string]any {
+	return map[string]any{"error": err.Error()}
+}